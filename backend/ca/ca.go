@@ -0,0 +1,162 @@
+// Package ca provides the certificate-authority plumbing behind mTLS agent
+// auth: loading the trusted bundle used to verify inbound client
+// certificates, and issuing new per-agent client certs signed by Asguard's
+// own CA.
+package ca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+)
+
+// LoadTrustPool reads a PEM-encoded bundle of trusted CA certificates from
+// disk and returns a pool suitable for tls.Config.ClientCAs. bundlePath
+// falls back to the CA_BUNDLE_PATH env var when empty.
+func LoadTrustPool(bundlePath string) (*x509.CertPool, error) {
+	if bundlePath == "" {
+		bundlePath = os.Getenv("CA_BUNDLE_PATH")
+	}
+	if bundlePath == "" {
+		return nil, fmt.Errorf("ca: no CA bundle configured (set CA_BUNDLE_PATH)")
+	}
+
+	pemBytes, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("ca: failed to read CA bundle %q: %w", bundlePath, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		return nil, fmt.Errorf("ca: no valid certificates found in bundle %q", bundlePath)
+	}
+
+	return pool, nil
+}
+
+// SigningCA holds Asguard's own CA certificate and private key, used by
+// cmd/certs to mint client certs for agents and webhooks.
+type SigningCA struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+}
+
+// LoadSigningCA reads the CA certificate and private key (both PEM) used to
+// sign agent client certificates. certPath/keyPath fall back to the
+// CA_CERT_PATH / CA_KEY_PATH env vars when empty.
+func LoadSigningCA(certPath, keyPath string) (*SigningCA, error) {
+	if certPath == "" {
+		certPath = os.Getenv("CA_CERT_PATH")
+	}
+	if keyPath == "" {
+		keyPath = os.Getenv("CA_KEY_PATH")
+	}
+	if certPath == "" || keyPath == "" {
+		return nil, fmt.Errorf("ca: CA_CERT_PATH and CA_KEY_PATH must both be set")
+	}
+
+	cert, err := readCertPEM(certPath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := readECKeyPEM(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningCA{Cert: cert, Key: key}, nil
+}
+
+func readCertPEM(path string) (*x509.Certificate, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ca: failed to read CA cert %q: %w", path, err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("ca: no PEM block found in %q", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("ca: failed to parse CA cert %q: %w", path, err)
+	}
+	return cert, nil
+}
+
+func readECKeyPEM(path string) (*ecdsa.PrivateKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ca: failed to read CA key %q: %w", path, err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("ca: no PEM block found in %q", path)
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("ca: failed to parse CA key %q: %w", path, err)
+	}
+	return key, nil
+}
+
+// IssuedCert is a freshly minted agent client certificate, PEM-encoded and
+// ready to hand to the agent, plus the metadata needed to register it.
+type IssuedCert struct {
+	CertPEM     []byte
+	KeyPEM      []byte
+	Fingerprint string // hex SHA-256 of the DER cert; doubles as its Firestore doc ID
+	NotAfter    time.Time
+}
+
+// IssueClientCert mints a new client certificate for agentID, valid for
+// validFor and signed by s. agentID becomes the cert's Subject CommonName,
+// so MTLSAuth can read the caller's identity straight off the verified chain.
+func (s *SigningCA) IssueClientCert(agentID string, validFor time.Duration) (*IssuedCert, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("ca: failed to generate agent key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("ca: failed to generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: agentID},
+		NotBefore:    now.Add(-5 * time.Minute), // small clock-skew buffer
+		NotAfter:     now.Add(validFor),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, s.Cert, &key.PublicKey, s.Key)
+	if err != nil {
+		return nil, fmt.Errorf("ca: failed to sign agent cert: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("ca: failed to marshal agent key: %w", err)
+	}
+
+	fingerprint := sha256.Sum256(der)
+
+	return &IssuedCert{
+		CertPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		Fingerprint: hex.EncodeToString(fingerprint[:]),
+		NotAfter:    template.NotAfter,
+	}, nil
+}
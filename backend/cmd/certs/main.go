@@ -0,0 +1,118 @@
+// Command certs issues and revokes per-agent mTLS client certificates and
+// registers their fingerprint + scopes in Firestore so middleware.MTLSAuth
+// can verify them on every request.
+//
+// Usage:
+//
+//	certs issue -agent=checkout-bot -scopes=analyze,rejudge -ttl=720h
+//	certs revoke -fingerprint=<hex>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"asguard/ca"
+	"asguard/services/db"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalf("usage: certs <issue|revoke> [flags]")
+	}
+
+	switch os.Args[1] {
+	case "issue":
+		issue(os.Args[2:])
+	case "revoke":
+		revoke(os.Args[2:])
+	default:
+		log.Fatalf("unknown subcommand %q (want issue|revoke)", os.Args[1])
+	}
+}
+
+func issue(args []string) {
+	fs := flag.NewFlagSet("issue", flag.ExitOnError)
+	agentID := fs.String("agent", "", "agent identity, becomes the cert's CommonName")
+	scopes := fs.String("scopes", "", "comma-separated list of allowed scopes")
+	ttl := fs.Duration("ttl", 30*24*time.Hour, "certificate validity period")
+	outDir := fs.String("out", ".", "directory to write <agent>.crt and <agent>.key to")
+	credsPath := fs.String("firestore-credentials", os.Getenv("FIRESTORE_CREDENTIALS"), "path to Firestore service account JSON")
+	fs.Parse(args)
+
+	if *agentID == "" {
+		log.Fatalf("issue: -agent is required")
+	}
+
+	signingCA, err := ca.LoadSigningCA("", "")
+	if err != nil {
+		log.Fatalf("issue: %v", err)
+	}
+
+	issued, err := signingCA.IssueClientCert(*agentID, *ttl)
+	if err != nil {
+		log.Fatalf("issue: %v", err)
+	}
+
+	certPath := fmt.Sprintf("%s/%s.crt", *outDir, *agentID)
+	keyPath := fmt.Sprintf("%s/%s.key", *outDir, *agentID)
+	if err := os.WriteFile(certPath, issued.CertPEM, 0o644); err != nil {
+		log.Fatalf("issue: failed to write %s: %v", certPath, err)
+	}
+	if err := os.WriteFile(keyPath, issued.KeyPEM, 0o600); err != nil {
+		log.Fatalf("issue: failed to write %s: %v", keyPath, err)
+	}
+
+	ctx := context.Background()
+	fsClient := db.NewFirestoreClient(ctx, *credsPath)
+	rec := db.AgentRecord{
+		AgentID:     *agentID,
+		Fingerprint: issued.Fingerprint,
+		Scopes:      splitScopes(*scopes),
+		IssuedAt:    time.Now(),
+		ExpiresAt:   issued.NotAfter,
+	}
+	if err := fsClient.PutAgentCert(ctx, rec); err != nil {
+		log.Fatalf("issue: %v", err)
+	}
+
+	fmt.Printf("issued cert for %q, fingerprint=%s, wrote %s and %s\n", *agentID, issued.Fingerprint, certPath, keyPath)
+}
+
+func revoke(args []string) {
+	fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+	fingerprint := fs.String("fingerprint", "", "fingerprint of the cert to revoke")
+	credsPath := fs.String("firestore-credentials", os.Getenv("FIRESTORE_CREDENTIALS"), "path to Firestore service account JSON")
+	fs.Parse(args)
+
+	if *fingerprint == "" {
+		log.Fatalf("revoke: -fingerprint is required")
+	}
+
+	ctx := context.Background()
+	fsClient := db.NewFirestoreClient(ctx, *credsPath)
+	if err := fsClient.RevokeAgentCert(ctx, *fingerprint); err != nil {
+		log.Fatalf("revoke: %v", err)
+	}
+
+	fmt.Printf("revoked cert %s\n", *fingerprint)
+}
+
+func splitScopes(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	parts := strings.Split(raw, ",")
+	scopes := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			scopes = append(scopes, p)
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,54 @@
+// Command worker runs the asynq server that processes queued transaction
+// analyses: the rule engine plus the Groq AI call, persisted back to
+// Firestore so GET /transactions/:id can report the final result.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"asguard/services/audit"
+	"asguard/services/db"
+	"asguard/services/queue"
+
+	"github.com/hibiken/asynq"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Printf("env variables werent found, using system vars")
+	}
+
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "127.0.0.1:6379"
+	}
+
+	credsPath := os.Getenv("FIRESTORE_CREDENTIALS")
+	if credsPath == "" {
+		log.Fatalf("FIRESTORE_CREDENTIALS must be set for cmd/worker")
+	}
+	fsClient := db.NewFirestoreClient(context.Background(), credsPath)
+
+	auditor, err := audit.NewFromEnv(fsClient)
+	if err != nil {
+		log.Fatalf("failed to set up audit log: %v", err)
+	}
+
+	handler := &queue.TransactionHandler{Store: fsClient, Auditor: auditor}
+
+	srv := asynq.NewServer(
+		asynq.RedisClientOpt{Addr: redisAddr},
+		asynq.Config{Concurrency: 10},
+	)
+
+	mux := asynq.NewServeMux()
+	mux.HandleFunc(queue.TypeAnalyzeTransaction, handler.HandleAnalyzeTransactionTask)
+
+	log.Printf("starting asguard worker, redis=%s", redisAddr)
+	if err := srv.Run(mux); err != nil {
+		log.Fatalf("worker stopped: %v", err)
+	}
+}
@@ -1,11 +1,24 @@
 package main
 
 import (
-	"asguard/routes" // importing the /routes package we created
+	"context"
+	"crypto/tls"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+
+	"asguard/ca"
+	"asguard/routes" // importing the /routes package we created
+	"asguard/services"
+	"asguard/services/audit"
+	"asguard/services/db"
+	"asguard/services/evidencestore"
+	"asguard/services/rules"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
 	"github.com/joho/godotenv"
 )
 
@@ -19,8 +32,66 @@ func main() {
 
 	router := gin.Default()
 
+	// Firestore is optional for local dev: without FIRESTORE_CREDENTIALS set,
+	// routes fall back to API-key-only auth instead of mTLS + API key.
+	var fsClient *db.FirestoreClient
+	if credsPath := os.Getenv("FIRESTORE_CREDENTIALS"); credsPath != "" {
+		fsClient = db.NewFirestoreClient(context.Background(), credsPath)
+	}
+
+	// Redis is optional too: without REDIS_ADDR, /analyze falls back to the
+	// original synchronous scoring path instead of enqueueing onto asynq.
+	var asyncClient *asynq.Client
+	if redisAddr := os.Getenv("REDIS_ADDR"); redisAddr != "" {
+		asyncClient = asynq.NewClient(asynq.RedisClientOpt{Addr: redisAddr})
+		defer asyncClient.Close()
+	}
+
+	// reloadRules rebuilds the active rule set from RULES_DIR and, when
+	// Firestore is configured, the rules collection, layered on top of the
+	// built-in rules. Run at startup, on SIGHUP, and from POST /rules/reload.
+	reloadRules := func() {
+		var extra []rules.RuleSource
+		if fsClient != nil {
+			docs, err := fsClient.ListRuleDocs(context.Background())
+			if err != nil {
+				log.Printf("[RULES] failed to load rules from Firestore: %v", err)
+			}
+			for _, d := range docs {
+				extra = append(extra, rules.RuleSource{Name: d.Name, Weight: d.Weight, Source: d.Source})
+			}
+		}
+		if err := services.ReloadRules(os.Getenv("RULES_DIR"), extra); err != nil {
+			log.Printf("[RULES] reload failed: %v", err)
+		}
+	}
+	reloadRules()
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			log.Printf("[RULES] SIGHUP received, reloading rules")
+			reloadRules()
+		}
+	}()
+
+	// Audit logging always writes locally; it also writes to Firestore
+	// once fsClient is configured, so GET /audit has something to query.
+	auditor, err := audit.NewFromEnv(fsClient)
+	if err != nil {
+		log.Fatalf("failed to set up audit log: %v", err)
+	}
+
+	// Evidence uploads are optional too: without EVIDENCE_S3_ENDPOINT set,
+	// the evidence routes respond 503 instead of streaming to a bucket.
+	evidenceStore, err := evidencestore.NewStoreFromEnv()
+	if err != nil {
+		log.Fatalf("failed to set up evidence store: %v", err)
+	}
+
 	// registers all routes
-	routes.RegisterRoutes(router)
+	routes.RegisterRoutes(router, fsClient, asyncClient, auditor, evidenceStore)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -30,5 +101,31 @@ func main() {
 		port = ":" + port
 	}
 
+	// When a trusted CA bundle is configured we serve over mTLS so agents and
+	// webhooks can authenticate with client certs instead of the shared
+	// ASGUARD_API_KEY. Without it we fall back to plain HTTP, same as before.
+	// VerifyClientCertIfGiven (not RequireAndVerifyClientCert) is
+	// deliberate: /analyze must accept API key OR client cert, so a caller
+	// with no cert at all still needs to complete the TLS handshake and
+	// reach middleware.RequireAPIKeyOrMTLS, which decides per request.
+	if pool, err := ca.LoadTrustPool(""); err == nil {
+		server := &http.Server{
+			Addr:    port,
+			Handler: router,
+			TLSConfig: &tls.Config{
+				ClientAuth: tls.VerifyClientCertIfGiven,
+				ClientCAs:  pool,
+			},
+		}
+		log.Printf("CA_BUNDLE_PATH set — starting asguard with mTLS enabled")
+		log.Fatal(server.ListenAndServeTLS(os.Getenv("TLS_CERT_PATH"), os.Getenv("TLS_KEY_PATH")))
+		return
+	} else if os.Getenv("CA_BUNDLE_PATH") != "" {
+		// CA_BUNDLE_PATH was set but the bundle couldn't be loaded — log it
+		// loudly rather than silently falling through to plain HTTP with no
+		// client-cert verification at all.
+		log.Printf("CA_BUNDLE_PATH is set but failed to load trust pool, falling back to plain HTTP without mTLS: %v", err)
+	}
+
 	router.Run(port)
 }
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin gates admin-only routes (rule reload, audit log access) on
+// top of RequireAPIKeyOrMTLS/APIKeyAuth. An mTLS caller needs "admin" in
+// its agent_scopes; an API-key caller needs the separate
+// ASGUARD_ADMIN_API_KEY header, since the shared ASGUARD_API_KEY carries
+// no scopes of its own.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scopesVal, ok := c.Get("agent_scopes"); ok {
+			if scopes, ok := scopesVal.([]string); ok {
+				for _, s := range scopes {
+					if s == "admin" {
+						c.Next()
+						return
+					}
+				}
+			}
+		}
+
+		if method, _ := c.Get("auth_method"); method == "api_key" {
+			adminKey := os.Getenv("ASGUARD_ADMIN_API_KEY")
+			if adminKey != "" && c.GetHeader("x-admin-api-key") == adminKey {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "admin access required",
+		})
+		c.Abort()
+	}
+}
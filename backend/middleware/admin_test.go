@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireAdminAllowsPlainAPIKeyAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("ASGUARD_API_KEY", "secret")
+	t.Setenv("ASGUARD_ADMIN_API_KEY", "admin-secret")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newRequestWithCert(nil)
+	c.Request.Header.Set("x-api-key", "secret")
+	c.Request.Header.Set("x-admin-api-key", "admin-secret")
+
+	// Firestore-optional deployments wire routes with plain APIKeyAuth(),
+	// not RequireAPIKeyOrMTLS — RequireAdmin must still recognize the
+	// resulting auth_method.
+	APIKeyAuth()(c)
+	if c.IsAborted() {
+		t.Fatalf("APIKeyAuth: unexpected abort, status %d", w.Code)
+	}
+
+	RequireAdmin()(c)
+	if c.IsAborted() {
+		t.Fatalf("RequireAdmin: expected a valid admin key to proceed, got status %d", w.Code)
+	}
+}
+
+func TestRequireAdminRejectsMissingAdminKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("ASGUARD_API_KEY", "secret")
+	t.Setenv("ASGUARD_ADMIN_API_KEY", "admin-secret")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newRequestWithCert(nil)
+	c.Request.Header.Set("x-api-key", "secret")
+
+	APIKeyAuth()(c)
+	if c.IsAborted() {
+		t.Fatalf("APIKeyAuth: unexpected abort, status %d", w.Code)
+	}
+
+	RequireAdmin()(c)
+	if !c.IsAborted() {
+		t.Fatal("RequireAdmin: expected a caller with no admin key to be rejected")
+	}
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireAdminAllowsMTLSAdminScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("agent_scopes", []string{"analyze", "admin"})
+
+	RequireAdmin()(c)
+	if c.IsAborted() {
+		t.Fatalf("RequireAdmin: expected an mTLS caller with the admin scope to proceed, got status %d", w.Code)
+	}
+}
+
+func TestRequireAdminRejectsNonAdminMTLSScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("agent_scopes", []string{"analyze"})
+
+	RequireAdmin()(c)
+	if !c.IsAborted() {
+		t.Fatal("RequireAdmin: expected an mTLS caller without the admin scope to be rejected")
+	}
+}
@@ -31,6 +31,7 @@ func APIKeyAuth() gin.HandlerFunc {
 		}
 
 		//if valid continue
+		c.Set("auth_method", "api_key")
 		c.Next()
 	}
 }
@@ -0,0 +1,25 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// RequireAPIKeyOrMTLS accepts either a valid x-api-key header or a verified
+// mTLS client certificate, so agents can move to cert-based identity without
+// a flag day. Either path tags the gin context with "auth_method" and
+// "agent_identity" so downstream handlers can log who made the call.
+func RequireAPIKeyOrMTLS(store agentStore) gin.HandlerFunc {
+	mtls := MTLSAuth(store)
+	apiKey := APIKeyAuth()
+
+	return func(c *gin.Context) {
+		if c.Request.TLS != nil && len(c.Request.TLS.PeerCertificates) > 0 {
+			mtls(c)
+			return
+		}
+
+		apiKey(c)
+		if !c.IsAborted() {
+			c.Set("auth_method", "api_key")
+			c.Set("agent_identity", "api_key_caller")
+		}
+	}
+}
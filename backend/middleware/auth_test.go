@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"asguard/services/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRequireAPIKeyOrMTLSFallsBackToAPIKey confirms a caller who completes
+// the TLS handshake without presenting a client cert (possible once the
+// server uses tls.VerifyClientCertIfGiven) still gets a chance to
+// authenticate with x-api-key instead of being rejected outright.
+func TestRequireAPIKeyOrMTLSFallsBackToAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("ASGUARD_API_KEY", "secret")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newRequestWithCert(nil)
+	c.Request.Header.Set("x-api-key", "secret")
+
+	RequireAPIKeyOrMTLS(&fakeAgentStore{})(c)
+
+	if c.IsAborted() {
+		t.Fatalf("expected request with a valid API key and no cert to proceed")
+	}
+	if method, _ := c.Get("auth_method"); method != "api_key" {
+		t.Errorf("auth_method = %v, want api_key", method)
+	}
+}
+
+func TestRequireAPIKeyOrMTLSUsesMTLSWhenCertPresent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	os.Unsetenv("ASGUARD_API_KEY")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newRequestWithCert(&x509.Certificate{Subject: pkix.Name{CommonName: "agent-1"}})
+
+	RequireAPIKeyOrMTLS(&fakeAgentStore{found: true, rec: db.AgentRecord{Scopes: []string{"analyze"}}})(c)
+
+	if c.IsAborted() {
+		t.Fatalf("expected request with a valid cert to proceed")
+	}
+	if method, _ := c.Get("auth_method"); method != "mtls" {
+		t.Errorf("auth_method = %v, want mtls", method)
+	}
+}
+
+func TestRequireAPIKeyOrMTLSRejectsBadAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("ASGUARD_API_KEY", "secret")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newRequestWithCert(nil)
+	c.Request.Header.Set("x-api-key", "wrong")
+
+	RequireAPIKeyOrMTLS(&fakeAgentStore{})(c)
+
+	if !c.IsAborted() {
+		t.Error("expected request with a wrong API key and no cert to be aborted")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
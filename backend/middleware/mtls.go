@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"asguard/services/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// agentStore is the subset of *db.FirestoreClient that MTLSAuth needs,
+// kept as an interface so it isn't wired to Firestore specifically.
+type agentStore interface {
+	GetAgentCert(ctx context.Context, fingerprint string) (db.AgentRecord, bool, error)
+}
+
+// MTLSAuth verifies that the caller presented a client certificate chaining
+// to our trusted CA (already enforced by the server's tls.Config) and that
+// the cert's fingerprint is a known, non-revoked agent. On success it sets
+// "agent_identity" (the cert's CommonName) and "agent_scopes" on the gin
+// context so handlers and audit logging know who called.
+func MTLSAuth(store agentStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.TLS == nil || len(c.Request.TLS.PeerCertificates) == 0 {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "client certificate required",
+			})
+			c.Abort()
+			return
+		}
+
+		cert := c.Request.TLS.PeerCertificates[0]
+		sum := sha256.Sum256(cert.Raw)
+		fingerprint := hex.EncodeToString(sum[:])
+
+		rec, found, err := store.GetAgentCert(c.Request.Context(), fingerprint)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "failed to verify agent certificate",
+			})
+			c.Abort()
+			return
+		}
+		if !found || rec.Revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "unknown or revoked client certificate",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("agent_identity", cert.Subject.CommonName)
+		c.Set("agent_scopes", rec.Scopes)
+		c.Set("auth_method", "mtls")
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"asguard/services/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAgentStore struct {
+	rec   db.AgentRecord
+	found bool
+	err   error
+}
+
+func (f *fakeAgentStore) GetAgentCert(ctx context.Context, fingerprint string) (db.AgentRecord, bool, error) {
+	return f.rec, f.found, f.err
+}
+
+func newRequestWithCert(cert *x509.Certificate) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/analyze", nil)
+	if cert != nil {
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	}
+	return req
+}
+
+func TestMTLSAuthNoCert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newRequestWithCert(nil)
+
+	MTLSAuth(&fakeAgentStore{})(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if !c.IsAborted() {
+		t.Error("expected context to be aborted without a client cert")
+	}
+}
+
+func TestMTLSAuthUnknownCert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newRequestWithCert(&x509.Certificate{Subject: pkix.Name{CommonName: "agent-1"}})
+
+	MTLSAuth(&fakeAgentStore{found: false})(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestMTLSAuthRevokedCert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newRequestWithCert(&x509.Certificate{Subject: pkix.Name{CommonName: "agent-1"}})
+
+	MTLSAuth(&fakeAgentStore{found: true, rec: db.AgentRecord{Revoked: true}})(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestMTLSAuthValidCert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = newRequestWithCert(&x509.Certificate{Subject: pkix.Name{CommonName: "agent-1"}})
+
+	MTLSAuth(&fakeAgentStore{found: true, rec: db.AgentRecord{Scopes: []string{"analyze"}}})(c)
+
+	if c.IsAborted() {
+		t.Fatalf("expected request to proceed for a valid, non-revoked cert")
+	}
+	if identity, _ := c.Get("agent_identity"); identity != "agent-1" {
+		t.Errorf("agent_identity = %v, want agent-1", identity)
+	}
+	if method, _ := c.Get("auth_method"); method != "mtls" {
+		t.Errorf("auth_method = %v, want mtls", method)
+	}
+}
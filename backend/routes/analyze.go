@@ -0,0 +1,118 @@
+package routes
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"asguard/services"
+	"asguard/services/audit"
+	"asguard/services/db"
+	"asguard/services/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+)
+
+type TransactionRequest struct {
+	UserID        string  `json:"user_id" binding:"required"`
+	TransactionID string  `json:"transaction_id" binding:"required"`
+	Amount        float64 `json:"amount" binding:"required"`
+	Currency      string  `json:"currency" binding:"required"`
+	IPAddress     string  `json:"ip_address" binding:"required"`
+	DeviceID      string  `json:"device_id" binding:"required"`
+	SimID         string  `json:"sim_id" binding:"required"`
+	Timestamp     string  `json:"timestamp" binding:"required"`
+}
+
+// AnalyzeTransaction returns the /analyze handler. When fsClient and
+// asyncClient are both configured it enqueues a TypeAnalyzeTransaction task
+// and returns immediately with a transaction_id + polling URL instead of
+// blocking on the Groq call. Without them it falls back to the original
+// synchronous scoring path. auditor (optional) records every decision for
+// later review; pass nil to disable.
+func AnalyzeTransaction(fsClient *db.FirestoreClient, asyncClient *asynq.Client, auditor audit.Auditor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var req TransactionRequest
+
+		// Bind incoming JSON into struct
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, gin.H{
+				"error": "invalid request payload",
+			})
+			return
+		}
+
+		// Log which identity made this call — an API key caller or, once
+		// mTLS is configured, the agent CommonName off the verified client
+		// cert.
+		identity, _ := c.Get("agent_identity")
+		callerIdentity := fmt.Sprintf("%v", identity)
+		log.Printf("[ANALYZE] caller=%v txn=%s", identity, req.TransactionID)
+
+		tx := services.TransactionData{
+			UserID:        req.UserID,
+			TransactionID: req.TransactionID,
+			Amount:        req.Amount,
+			Currency:      req.Currency,
+			IPAddress:     req.IPAddress,
+			DeviceID:      req.DeviceID,
+		}
+
+		if asyncClient == nil || fsClient == nil {
+			riskResult := services.CalculateRisk(tx, nil)
+
+			if auditor != nil {
+				entry := audit.Entry{
+					TransactionID:  req.TransactionID,
+					CallerIdentity: callerIdentity,
+					Request:        tx,
+					RuleScores:     riskResult.RuleScores,
+					AIPrompt:       riskResult.AIPrompt,
+					AIRawResponse:  riskResult.AIRawResponse,
+					Result:         riskResult,
+				}
+				if err := auditor.Append(c.Request.Context(), entry); err != nil {
+					log.Printf("[ANALYZE] failed to append audit entry for txn=%s: %v", req.TransactionID, err)
+				}
+			}
+
+			c.JSON(200, gin.H{
+				"transaction_id": req.TransactionID,
+				"risk_score":     riskResult.Score,
+				"risk_level":     riskResult.Level,
+				"reasons":        riskResult.Reasons,
+				"ai_confidence":  riskResult.AIConfidence,
+				"ai_summary":     riskResult.AISummary,
+				"message":        "Transaction received successfully",
+			})
+			return
+		}
+
+		if err := fsClient.CreateTransactionPending(c.Request.Context(), tx); err != nil {
+			log.Printf("[ANALYZE] failed to record txn=%s: %v", req.TransactionID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record transaction"})
+			return
+		}
+
+		task, err := queue.NewAnalyzeTransactionTask(tx, "", callerIdentity)
+		if err != nil {
+			log.Printf("[ANALYZE] failed to build task for txn=%s: %v", req.TransactionID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to enqueue transaction"})
+			return
+		}
+		if _, err := asyncClient.Enqueue(task); err != nil {
+			log.Printf("[ANALYZE] failed to enqueue txn=%s: %v", req.TransactionID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to enqueue transaction"})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"transaction_id": req.TransactionID,
+			"status":         "pending",
+			"poll_url":       "/transactions/" + req.TransactionID,
+			"message":        "Transaction queued for analysis",
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"asguard/services/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuditLog returns the admin GET /audit handler: every audit record
+// with a timestamp in the optional ?from=&to= range (RFC3339), so a
+// reviewer can see exactly what was scored, by whom, and on what
+// rule-engine/AI basis.
+func GetAuditLog(fsClient *db.FirestoreClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if fsClient == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "audit log queries require Firestore to be configured",
+			})
+			return
+		}
+
+		var from, to time.Time
+		if raw := c.Query("from"); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'from' timestamp, want RFC3339"})
+				return
+			}
+			from = parsed
+		}
+		if raw := c.Query("to"); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'to' timestamp, want RFC3339"})
+				return
+			}
+			to = parsed
+		}
+
+		records, err := fsClient.ListAuditRecords(c.Request.Context(), from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to query audit log"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"records": records})
+	}
+}
@@ -0,0 +1,143 @@
+package routes
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"asguard/services"
+	"asguard/services/db"
+	"asguard/services/evidencestore"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// evidencePresignTTL is how long a GET /analyze/:txid/evidence/:key
+// download URL stays valid.
+const evidencePresignTTL = 15 * time.Minute
+
+// UploadEvidence returns the POST /analyze/:txid/evidence handler: it
+// streams a multipart file upload into the evidence store under
+// evidence/{user_id}/{txid}/{uuid} and records its object key and content
+// hash on the transaction's Firestore doc.
+func UploadEvidence(fsClient *db.FirestoreClient, store *evidencestore.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if fsClient == nil || store == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "evidence uploads require Firestore and an evidence store to be configured",
+			})
+			return
+		}
+
+		txID := c.Param("txid")
+		rec, found, err := fsClient.GetTransaction(c.Request.Context(), txID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to look up transaction"})
+			return
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+			return
+		}
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'file' in multipart form"})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read uploaded file"})
+			return
+		}
+		defer file.Close()
+
+		contentType := fileHeader.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		key := fmt.Sprintf("evidence/%s/%s/%s", rec.Tx.UserID, txID, uuid.NewString())
+
+		sum, err := store.Upload(c.Request.Context(), key, file, fileHeader.Size, contentType)
+		if err != nil {
+			log.Printf("[EVIDENCE] upload failed for txn=%s: %v", txID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to upload evidence"})
+			return
+		}
+
+		identity, _ := c.Get("agent_identity")
+		entry := services.EvidenceRecord{
+			Key:              key,
+			Filename:         fileHeader.Filename,
+			ContentType:      contentType,
+			SHA256:           sum,
+			Size:             fileHeader.Size,
+			UploaderIdentity: fmt.Sprintf("%v", identity),
+			UploadedAt:       time.Now(),
+		}
+		if err := fsClient.AddEvidence(c.Request.Context(), txID, entry); err != nil {
+			log.Printf("[EVIDENCE] failed to record metadata for txn=%s: %v", txID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record evidence metadata"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"key":    key,
+			"sha256": sum,
+			"size":   fileHeader.Size,
+		})
+	}
+}
+
+// GetEvidence returns the GET /analyze/:txid/evidence/:key handler: it
+// checks the object was actually uploaded for this transaction, then
+// hands back a short-lived pre-signed download URL instead of proxying
+// the bytes itself.
+func GetEvidence(fsClient *db.FirestoreClient, store *evidencestore.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if fsClient == nil || store == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "evidence retrieval requires Firestore and an evidence store to be configured",
+			})
+			return
+		}
+
+		txID := c.Param("txid")
+		rec, found, err := fsClient.GetTransaction(c.Request.Context(), txID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to look up transaction"})
+			return
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+			return
+		}
+
+		objectKey := fmt.Sprintf("evidence/%s/%s/%s", rec.Tx.UserID, txID, c.Param("key"))
+		known := false
+		for _, e := range rec.Evidence {
+			if e.Key == objectKey {
+				known = true
+				break
+			}
+		}
+		if !known {
+			c.JSON(http.StatusNotFound, gin.H{"error": "evidence not found for this transaction"})
+			return
+		}
+
+		url, err := store.PresignGet(c.Request.Context(), objectKey, evidencePresignTTL)
+		if err != nil {
+			log.Printf("[EVIDENCE] presign failed for txn=%s key=%s: %v", txID, objectKey, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate download URL"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"url":                url,
+			"expires_in_seconds": int(evidencePresignTTL.Seconds()),
+		})
+	}
+}
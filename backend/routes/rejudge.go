@@ -0,0 +1,92 @@
+package routes
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"asguard/services/db"
+	"asguard/services/queue"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+)
+
+// RejudgeRequest narrows which past transactions to re-enqueue. Every
+// field is optional; an empty request matches everything, so callers
+// should scope at least one of UserID/RuleVersion/From/To in practice.
+type RejudgeRequest struct {
+	UserID      string `json:"user_id"`
+	RuleVersion string `json:"rule_version"`
+	From        string `json:"from"` // RFC3339
+	To          string `json:"to"`   // RFC3339
+}
+
+// RejudgeTransactions returns the POST /rejudge handler: it scans Firestore
+// for transactions matching the request and re-enqueues each one so that
+// when CalculateRisk's weights or the AI prompt change, historical
+// decisions can be recomputed with the new logic.
+func RejudgeTransactions(fsClient *db.FirestoreClient, asyncClient *asynq.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if fsClient == nil || asyncClient == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "rejudge requires Firestore and Redis to be configured",
+			})
+			return
+		}
+
+		var req RejudgeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(400, gin.H{"error": "invalid request payload"})
+			return
+		}
+
+		filter := db.RejudgeFilter{UserID: req.UserID, RuleVersion: req.RuleVersion}
+		if req.From != "" {
+			from, err := time.Parse(time.RFC3339, req.From)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid 'from' timestamp, want RFC3339"})
+				return
+			}
+			filter.From = from
+		}
+		if req.To != "" {
+			to, err := time.Parse(time.RFC3339, req.To)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid 'to' timestamp, want RFC3339"})
+				return
+			}
+			filter.To = to
+		}
+
+		records, err := fsClient.QueryForRejudge(c.Request.Context(), filter)
+		if err != nil {
+			log.Printf("[REJUDGE] query failed: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to query transactions"})
+			return
+		}
+
+		identity, _ := c.Get("agent_identity")
+		callerIdentity := fmt.Sprintf("rejudge:%v", identity)
+
+		requeued := 0
+		for _, rec := range records {
+			task, err := queue.NewAnalyzeTransactionTask(rec.Tx, req.RuleVersion, callerIdentity)
+			if err != nil {
+				log.Printf("[REJUDGE] failed to build task for txn=%s: %v", rec.TransactionID, err)
+				continue
+			}
+			if _, err := asyncClient.Enqueue(task); err != nil {
+				log.Printf("[REJUDGE] failed to enqueue txn=%s: %v", rec.TransactionID, err)
+				continue
+			}
+			requeued++
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"matched":  len(records),
+			"requeued": requeued,
+		})
+	}
+}
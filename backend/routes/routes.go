@@ -2,59 +2,25 @@ package routes
 
 import (
 	"asguard/middleware"
-	"asguard/services"
+	"asguard/services/audit"
+	"asguard/services/db"
+	"asguard/services/evidencestore"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
 )
 
-type TransactionRequest struct {
-	UserID        string  `json:"user_id" binding:"required"`
-	TransactionID string  `json:"transaction_id" binding:"required"`
-	Amount        float64 `json:"amount" binding:"required"`
-	Currency      string  `json:"currency" binding:"required"`
-	IPAddress     string  `json:"ip_address" binding:"required"`
-	DeviceID      string  `json:"device_id" binding:"required"`
-	SimID         string  `json:"sim_id" binding:"required"`
-	Timestamp     string  `json:"timestamp" binding:"required"`
-}
-
-func AnalyzeTransaction(c *gin.Context) {
-
-	var req TransactionRequest
-
-	// Bind incoming JSON into struct
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{
-			"error": "invalid request payload",
-		})
-		return
-	}
-
-	// Call service layer AFTER validation succeeds
-	riskResult := services.CalculateRisk(services.TransactionData{
-		UserID:        req.UserID,
-		TransactionID: req.TransactionID,
-		Amount:        req.Amount,
-		Currency:      req.Currency,
-		IPAddress:     req.IPAddress,
-		DeviceID:      req.DeviceID,
-	})
-
-	// Return structured response
-	c.JSON(200, gin.H{
-		"transaction_id": req.TransactionID,
-		"risk_score":     riskResult.Score,
-		"risk_level":     riskResult.Level,
-		"reasons":        riskResult.Reasons,
-		"ai_confidence":  riskResult.AIConfidence,
-		"ai_summary":     riskResult.AISummary,
-		"message":        "Transaction received successfully",
-	})
-}
-
 // this function  job is to receive a JSON object, check if it's valid, and send a response back.
 
-func RegisterRoutes(router *gin.Engine) {
+// RegisterRoutes wires up every route. fsClient, asyncClient, and
+// evidenceStore are all optional: pass nil for any of them to keep the
+// original synchronous, API-key-only behaviour for local dev without
+// Firestore/Redis/an evidence store configured. With fsClient and
+// asyncClient both set, /analyze enqueues onto asynq instead of blocking
+// and /rejudge becomes available. With fsClient set, protected routes
+// additionally accept verified mTLS client certs. auditor is also
+// optional; pass nil to disable audit logging.
+func RegisterRoutes(router *gin.Engine, fsClient *db.FirestoreClient, asyncClient *asynq.Client, auditor audit.Auditor, evidenceStore *evidencestore.Store) {
 
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -75,12 +41,30 @@ func RegisterRoutes(router *gin.Engine) {
 	// By using "/", you aren't changing the URL (it's still just localhost:8081/),
 	// but you are creating a logical "bucket" for your protected routes.
 
-	protected.Use(middleware.APIKeyAuth())
+	if fsClient != nil {
+		protected.Use(middleware.RequireAPIKeyOrMTLS(fsClient))
+	} else {
+		protected.Use(middleware.APIKeyAuth())
+	}
 	{
-		protected.POST("/analyze", AnalyzeTransaction)
+		protected.POST("/analyze", AnalyzeTransaction(fsClient, asyncClient, auditor))
+		protected.GET("/transactions/:id", GetTransactionStatus(fsClient))
+		protected.POST("/rejudge", RejudgeTransactions(fsClient, asyncClient))
+		protected.POST("/analyze/:txid/evidence", UploadEvidence(fsClient, evidenceStore))
+		protected.GET("/analyze/:txid/evidence/:key", GetEvidence(fsClient, evidenceStore))
 		protected.GET("/secure-test", func(c *gin.Context) {
 			c.JSON(200, gin.H{
 				"message": "API key valid"})
 		})
 	}
+
+	// Admin routes sit on top of the same auth as protected (API key or
+	// mTLS) plus a role check: rule reloads and the audit trail are
+	// sensitive enough that every caller shouldn't have them by default.
+	admin := protected.Group("/")
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.POST("/rules/reload", ReloadRulesHandler(fsClient))
+		admin.GET("/audit", GetAuditLog(fsClient))
+	}
 }
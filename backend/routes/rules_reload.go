@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"net/http"
+	"os"
+
+	"asguard/services"
+	"asguard/services/db"
+	"asguard/services/rules"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReloadRulesHandler returns the admin POST /rules/reload handler: it
+// rebuilds the active rule set from the RULES_DIR directory and, when
+// Firestore is configured, the rules collection — the same sources
+// main.go loads from on startup and SIGHUP.
+func ReloadRulesHandler(fsClient *db.FirestoreClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var extra []rules.RuleSource
+		if fsClient != nil {
+			docs, err := fsClient.ListRuleDocs(c.Request.Context())
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load rules from Firestore"})
+				return
+			}
+			for _, d := range docs {
+				extra = append(extra, rules.RuleSource{Name: d.Name, Weight: d.Weight, Source: d.Source})
+			}
+		}
+
+		if err := services.ReloadRules(os.Getenv("RULES_DIR"), extra); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reload rules"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"rule_version": services.DefaultRegistry.Version(),
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"net/http"
+
+	"asguard/services/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTransactionStatus returns the GET /transactions/:id handler clients
+// poll after an async /analyze call to retrieve the final RiskResult.
+func GetTransactionStatus(fsClient *db.FirestoreClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if fsClient == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "transaction polling requires Firestore to be configured",
+			})
+			return
+		}
+
+		txID := c.Param("id")
+		rec, found, err := fsClient.GetTransaction(c.Request.Context(), txID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to look up transaction"})
+			return
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"transaction_id": rec.TransactionID,
+			"status":         rec.Status,
+			"rule_version":   rec.RuleVersion,
+			"result":         rec.Result,
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -18,6 +19,14 @@ type AIResult struct {
 	Confidence        float64 `json:"confidence"`
 }
 
+// AIInteraction is the exact prompt sent to Groq and the raw response text
+// it returned, kept alongside the parsed AIResult so the audit subsystem
+// can record precisely what the AI saw and said.
+type AIInteraction struct {
+	Prompt      string
+	RawResponse string
+}
+
 type groqRequest struct {
 	Model       string        `json:"model"`
 	Messages    []groqMessage `json:"messages"`
@@ -41,12 +50,32 @@ type groqResponse struct {
 	} `json:"error,omitempty"`
 }
 
-// AnalyzeTransaction sends transaction data to Groq AI and returns a structured risk assessment.
-func AnalyzeTransaction(tx TransactionData, baselineScore int) (AIResult, error) {
+// formatEvidenceForPrompt renders uploaded evidence metadata as a section
+// of the Groq user prompt, or "" when there's none to mention.
+func formatEvidenceForPrompt(evidence []EvidenceRecord) string {
+	if len(evidence) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nEvidence attached to this transaction:\n")
+	for _, e := range evidence {
+		fmt.Fprintf(&b, "- %s (%s, %d bytes, sha256=%s, uploaded by %s)\n",
+			e.Filename, e.ContentType, e.Size, e.SHA256, e.UploaderIdentity)
+	}
+	return b.String()
+}
+
+// AnalyzeTransaction sends transaction data to Groq AI and returns a
+// structured risk assessment plus the exact prompt/response exchanged, for
+// the audit trail. evidence (optional) is summarized into the prompt so
+// the AI can factor in uploaded artifacts like a device fingerprint
+// mismatch or a failed 3DS challenge.
+func AnalyzeTransaction(tx TransactionData, baselineScore int, evidence []EvidenceRecord) (AIResult, AIInteraction, error) {
 
 	apiKey := os.Getenv("GROQ_API_KEY")
 	if apiKey == "" {
-		return AIResult{}, fmt.Errorf("GROQ_API_KEY is not set in environment")
+		return AIResult{}, AIInteraction{}, fmt.Errorf("GROQ_API_KEY is not set in environment")
 	}
 
 	// System prompt: tells the AI its role and output format strictly
@@ -70,7 +99,7 @@ Location       : %s
 Device ID      : %s
 IP Address     : %s
 Baseline Score : %d/100 (rule-based engine score, higher = riskier)
-
+%s
 Respond with JSON only.`,
 		tx.TransactionID,
 		tx.Amount,
@@ -79,6 +108,7 @@ Respond with JSON only.`,
 		tx.DeviceID,
 		tx.IPAddress,
 		baselineScore,
+		formatEvidenceForPrompt(evidence),
 	)
 
 	body := groqRequest{
@@ -92,14 +122,20 @@ Respond with JSON only.`,
 		},
 	}
 
+	// Recorded on every return path from here on, so the audit trail has the
+	// exact prompt sent to Groq even when the call fails before a response
+	// arrives. The earlier missing-GROQ_API_KEY return sends nothing and
+	// returns an empty AIInteraction.
+	interaction := AIInteraction{Prompt: systemPrompt + "\n\n" + userPrompt}
+
 	jsonData, err := json.Marshal(body)
 	if err != nil {
-		return AIResult{}, fmt.Errorf("failed to marshal Groq request: %w", err)
+		return AIResult{}, interaction, fmt.Errorf("failed to marshal Groq request: %w", err)
 	}
 
 	httpReq, err := http.NewRequest("POST", "https://api.groq.com/openai/v1/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return AIResult{}, fmt.Errorf("failed to create HTTP request: %w", err)
+		return AIResult{}, interaction, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
@@ -109,22 +145,28 @@ Respond with JSON only.`,
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(httpReq)
 	if err != nil {
-		return AIResult{}, fmt.Errorf("Groq HTTP request failed: %w", err)
+		return AIResult{}, interaction, fmt.Errorf("Groq HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	rawBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return AIResult{}, interaction, fmt.Errorf("failed to read Groq response: %w", err)
+	}
+	interaction.RawResponse = string(rawBody)
+
 	var groqResp groqResponse
-	if err := json.NewDecoder(resp.Body).Decode(&groqResp); err != nil {
-		return AIResult{}, fmt.Errorf("failed to decode Groq response: %w", err)
+	if err := json.Unmarshal(rawBody, &groqResp); err != nil {
+		return AIResult{}, interaction, fmt.Errorf("failed to decode Groq response: %w", err)
 	}
 
 	// Check if Groq returned an API-level error (e.g. invalid key, rate limit)
 	if groqResp.Error != nil {
-		return AIResult{}, fmt.Errorf("Groq API error: %s", groqResp.Error.Message)
+		return AIResult{}, interaction, fmt.Errorf("Groq API error: %s", groqResp.Error.Message)
 	}
 
 	if len(groqResp.Choices) == 0 {
-		return AIResult{}, fmt.Errorf("Groq returned no choices")
+		return AIResult{}, interaction, fmt.Errorf("Groq returned no choices")
 	}
 
 	rawContent := strings.TrimSpace(groqResp.Choices[0].Message.Content)
@@ -137,7 +179,7 @@ Respond with JSON only.`,
 
 	var aiResult AIResult
 	if err := json.Unmarshal([]byte(rawContent), &aiResult); err != nil {
-		return AIResult{}, fmt.Errorf("AI returned invalid JSON (%q): %w", rawContent, err)
+		return AIResult{}, interaction, fmt.Errorf("AI returned invalid JSON (%q): %w", rawContent, err)
 	}
 
 	// Validate the recommended action is one of the expected values
@@ -145,8 +187,8 @@ Respond with JSON only.`,
 	case "APPROVE", "REVIEW", "BLOCK":
 		// valid
 	default:
-		return AIResult{}, fmt.Errorf("AI returned unexpected action: %q", aiResult.RecommendedAction)
+		return AIResult{}, interaction, fmt.Errorf("AI returned unexpected action: %q", aiResult.RecommendedAction)
 	}
 
-	return aiResult, nil
+	return aiResult, interaction, nil
 }
@@ -0,0 +1,82 @@
+// Package audit records a tamper-evident trail of every risk decision:
+// the original request, who made it, the rule-engine breakdown, the exact
+// Groq prompt/response, and the final RiskResult. Each Auditor
+// implementation chains its own records by hash so an altered or deleted
+// entry breaks the chain from that point on.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"asguard/services"
+	"asguard/services/rules"
+)
+
+// Entry is one audit record. Callers only need to populate the content
+// fields (everything before Timestamp) — the Auditor implementation fills
+// in Timestamp, Sequence, PrevHash, and Hash before persisting.
+type Entry struct {
+	TransactionID  string                   `json:"transaction_id"`
+	CallerIdentity string                   `json:"caller_identity"`
+	Request        services.TransactionData `json:"request"`
+	RuleScores     []rules.RuleScore        `json:"rule_scores,omitempty"`
+	AIPrompt       string                   `json:"ai_prompt,omitempty"`
+	AIRawResponse  string                   `json:"ai_raw_response,omitempty"`
+	Result         services.RiskResult      `json:"result"`
+	Timestamp      time.Time                `json:"timestamp"`
+
+	Sequence int64  `json:"sequence"`
+	PrevHash string `json:"prev_hash"`
+	Hash     string `json:"hash"`
+}
+
+// Auditor appends a single audit record. Implementations must be safe for
+// concurrent use within one process, and — since the API server and
+// cmd/worker are separate OS processes that both append to the same
+// sink — safe for concurrent use ACROSS processes too: Sequence and
+// PrevHash must be derived from the sink's actual current state at
+// Append time, never cached in memory across calls.
+type Auditor interface {
+	Append(ctx context.Context, e Entry) error
+}
+
+// computeHash hashes everything in e except Hash itself, so the chain
+// detects any modification to a record's content, sequence, or prev_hash.
+func computeHash(e Entry) string {
+	e.Hash = ""
+	// Entry round-trips through JSON deterministically: encoding/json
+	// marshals struct fields in declaration order, so this is stable
+	// across calls for the same content.
+	raw, err := json.Marshal(e)
+	if err != nil {
+		// Entry contains only JSON-safe types (strings, slices, structs,
+		// times), so Marshal failing here would mean a programmer error,
+		// not bad input — fall back to hashing the error text so Append
+		// still produces *a* chain rather than panicking.
+		raw = []byte(fmt.Sprintf("audit: failed to marshal entry for hashing: %v", err))
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// MultiAuditor fans a single Append out to every configured Auditor — e.g.
+// a local JSONL file for on-disk tamper evidence even when Firestore is
+// down, plus Firestore so GET /audit can query by time range. It attempts
+// every sink before returning so one failing auditor doesn't suppress the
+// others, and reports the first error encountered (if any).
+type MultiAuditor []Auditor
+
+func (m MultiAuditor) Append(ctx context.Context, e Entry) error {
+	var firstErr error
+	for _, a := range m {
+		if err := a.Append(ctx, e); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FileAuditor appends audit records as newline-delimited JSON to a local,
+// append-only log file that rotates daily (audit-2026-07-25.jsonl). It's
+// the always-on sink: it needs no external infra, so the trail survives
+// even when Firestore is unreachable.
+//
+// The API server and the worker are separate OS processes that can both
+// be appending to the same file at once, so an in-process mutex alone
+// can't stop them from racing each other into duplicate sequence numbers
+// or a broken hash chain: every Append takes an exclusive flock on the
+// file and re-derives the chain tail from its last line under that lock,
+// rather than trusting any cached sequence/hash.
+type FileAuditor struct {
+	dir string
+	mu  sync.Mutex // serializes this process's own Appends before they contend for the flock
+}
+
+// NewFileAuditor creates the log directory (if needed) and returns a
+// FileAuditor rooted at it.
+func NewFileAuditor(dir string) (*FileAuditor, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("audit: failed to create log directory %s: %w", dir, err)
+	}
+	return &FileAuditor{dir: dir}, nil
+}
+
+// Append writes e to today's log file, filling in Sequence, PrevHash, and
+// Hash from the file's last line.
+func (a *FileAuditor) Append(ctx context.Context, e Entry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	path := filepath.Join(a.dir, fmt.Sprintf("audit-%s.jsonl", time.Now().UTC().Format("2006-01-02")))
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("audit: failed to open log file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("audit: failed to lock log file %s: %w", path, err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	seq, lastHash, err := lastEntry(f)
+	if err != nil {
+		return err
+	}
+
+	e.Timestamp = time.Now()
+	e.Sequence = seq + 1
+	e.PrevHash = lastHash
+	e.Hash = computeHash(e)
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("audit: failed to marshal entry %d: %w", e.Sequence, err)
+	}
+	// f was opened O_APPEND, so this write always lands at EOF regardless
+	// of the Seek lastEntry just did to read from the start.
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("audit: failed to write entry %d: %w", e.Sequence, err)
+	}
+	return nil
+}
+
+// lastEntry scans f for its last line and returns the sequence and hash
+// recorded there, or zero values if f is empty. f must already be
+// flock'd by the caller.
+func lastEntry(f *os.File) (int64, string, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, "", fmt.Errorf("audit: failed to seek log file: %w", err)
+	}
+
+	var last Entry
+	found := false
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return 0, "", fmt.Errorf("audit: failed to parse existing log entry: %w", err)
+		}
+		last = e
+		found = true
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, "", fmt.Errorf("audit: failed to read log file: %w", err)
+	}
+	if !found {
+		return 0, "", nil
+	}
+	return last.Sequence, last.Hash, nil
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"asguard/services/db"
+)
+
+// auditStore is the subset of *db.FirestoreClient that FirestoreAuditor
+// needs, kept as an interface the same way middleware.agentStore is.
+type auditStore interface {
+	AppendAuditRecordAtomic(ctx context.Context, build func(prevSeq int64, prevHash string) db.AuditRecord) (db.AuditRecord, error)
+}
+
+// FirestoreAuditor persists audit records to Firestore's audit_log
+// collection, so GET /audit can query by time range across every process
+// that's writing to it. It deliberately keeps no in-memory sequence/hash
+// state: the API server and the worker are separate OS processes both
+// appending to the same collection, so each Append reads the chain tail
+// and writes the next record in a single Firestore transaction instead —
+// see AppendAuditRecordAtomic.
+type FirestoreAuditor struct {
+	store auditStore
+}
+
+// NewFirestoreAuditor returns a FirestoreAuditor backed by store.
+func NewFirestoreAuditor(store auditStore) *FirestoreAuditor {
+	return &FirestoreAuditor{store: store}
+}
+
+func (a *FirestoreAuditor) Append(ctx context.Context, e Entry) error {
+	_, err := a.store.AppendAuditRecordAtomic(ctx, func(prevSeq int64, prevHash string) db.AuditRecord {
+		e.Timestamp = time.Now()
+		e.Sequence = prevSeq + 1
+		e.PrevHash = prevHash
+		e.Hash = computeHash(e)
+
+		return db.AuditRecord{
+			TransactionID:  e.TransactionID,
+			CallerIdentity: e.CallerIdentity,
+			Request:        e.Request,
+			RuleScores:     e.RuleScores,
+			AIPrompt:       e.AIPrompt,
+			AIRawResponse:  e.AIRawResponse,
+			Result:         e.Result,
+			Timestamp:      e.Timestamp,
+			Sequence:       e.Sequence,
+			PrevHash:       e.PrevHash,
+			Hash:           e.Hash,
+		}
+	})
+	return err
+}
@@ -0,0 +1,31 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+
+	"asguard/services/db"
+)
+
+// NewFromEnv builds the process's Auditor: a local JSONL auditor rooted at
+// AUDIT_LOG_DIR (default "./audit-log") is always present, and a
+// FirestoreAuditor is layered on top of it via a MultiAuditor when
+// fsClient is configured. Both main.go and cmd/worker call this so the API
+// and the worker write to the same sinks.
+func NewFromEnv(fsClient *db.FirestoreClient) (Auditor, error) {
+	dir := os.Getenv("AUDIT_LOG_DIR")
+	if dir == "" {
+		dir = "./audit-log"
+	}
+
+	fileAuditor, err := NewFileAuditor(dir)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to set up file auditor: %w", err)
+	}
+
+	if fsClient == nil {
+		return fileAuditor, nil
+	}
+
+	return MultiAuditor{fileAuditor, NewFirestoreAuditor(fsClient)}, nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const agentCertsCollection = "agent_certs"
+
+// AgentRecord is what we persist per issued client certificate: its
+// fingerprint (doubles as the Firestore doc ID), the scopes it's allowed to
+// use, and its revocation state.
+type AgentRecord struct {
+	AgentID     string    `firestore:"agent_id"`
+	Fingerprint string    `firestore:"fingerprint"`
+	Scopes      []string  `firestore:"scopes"`
+	IssuedAt    time.Time `firestore:"issued_at"`
+	ExpiresAt   time.Time `firestore:"expires_at"`
+	Revoked     bool      `firestore:"revoked"`
+}
+
+// PutAgentCert stores a freshly issued cert's fingerprint and scopes so
+// MTLSAuth can look it up on every request.
+func (f *FirestoreClient) PutAgentCert(ctx context.Context, rec AgentRecord) error {
+	_, err := f.Client.Collection(agentCertsCollection).Doc(rec.Fingerprint).Set(ctx, rec)
+	if err != nil {
+		return fmt.Errorf("db: failed to store agent cert %s: %w", rec.Fingerprint, err)
+	}
+	return nil
+}
+
+// GetAgentCert looks up an agent cert by its fingerprint. It returns
+// found=false (no error) when the fingerprint isn't registered at all.
+func (f *FirestoreClient) GetAgentCert(ctx context.Context, fingerprint string) (AgentRecord, bool, error) {
+	doc, err := f.Client.Collection(agentCertsCollection).Doc(fingerprint).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return AgentRecord{}, false, nil
+	}
+	if err != nil {
+		return AgentRecord{}, false, fmt.Errorf("db: failed to look up agent cert %s: %w", fingerprint, err)
+	}
+
+	var rec AgentRecord
+	if err := doc.DataTo(&rec); err != nil {
+		return AgentRecord{}, false, fmt.Errorf("db: failed to decode agent cert %s: %w", fingerprint, err)
+	}
+	return rec, true, nil
+}
+
+// RevokeAgentCert marks a previously issued cert as revoked so MTLSAuth
+// rejects it even though it's still cryptographically valid.
+func (f *FirestoreClient) RevokeAgentCert(ctx context.Context, fingerprint string) error {
+	_, err := f.Client.Collection(agentCertsCollection).Doc(fingerprint).Set(ctx, map[string]interface{}{
+		"revoked": true,
+	}, firestore.MergeAll)
+	if err != nil {
+		return fmt.Errorf("db: failed to revoke agent cert %s: %w", fingerprint, err)
+	}
+	return nil
+}
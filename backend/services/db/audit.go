@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"asguard/services"
+	"asguard/services/rules"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+const auditCollection = "audit_log"
+
+// AuditRecord is one tamper-evident audit entry persisted to Firestore:
+// the full request, who made it, the rule-engine breakdown, the exact AI
+// prompt/response, and the final result, chained to the previous record's
+// hash.
+type AuditRecord struct {
+	TransactionID  string                   `firestore:"transaction_id"`
+	CallerIdentity string                   `firestore:"caller_identity"`
+	Request        services.TransactionData `firestore:"request"`
+	RuleScores     []rules.RuleScore        `firestore:"rule_scores,omitempty"`
+	AIPrompt       string                   `firestore:"ai_prompt,omitempty"`
+	AIRawResponse  string                   `firestore:"ai_raw_response,omitempty"`
+	Result         services.RiskResult      `firestore:"result"`
+	Timestamp      time.Time                `firestore:"timestamp"`
+	Sequence       int64                    `firestore:"sequence"`
+	PrevHash       string                   `firestore:"prev_hash"`
+	Hash           string                   `firestore:"hash"`
+}
+
+// AppendAuditRecordAtomic reads the current chain tail and appends a new
+// record inside a single Firestore transaction. build receives the last
+// record's sequence and hash (zero values if the collection is empty) and
+// returns the record to persist. Doing the read and the write in one
+// transaction is what makes this safe to call from multiple OS processes
+// at once (the API server and the worker both write to audit_log):
+// Firestore detects the conflict between two concurrent transactions
+// reading the same tail and retries the loser, so two processes can never
+// both claim the same sequence number or chain off the same prev_hash.
+func (f *FirestoreClient) AppendAuditRecordAtomic(ctx context.Context, build func(prevSeq int64, prevHash string) AuditRecord) (AuditRecord, error) {
+	var rec AuditRecord
+	err := f.Client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		q := f.Client.Collection(auditCollection).OrderBy("sequence", firestore.Desc).Limit(1)
+		iter := tx.Documents(q)
+		defer iter.Stop()
+
+		var prevSeq int64
+		var prevHash string
+		doc, err := iter.Next()
+		if err != nil && err != iterator.Done {
+			return fmt.Errorf("db: failed to look up last audit record: %w", err)
+		}
+		if err == nil {
+			var last AuditRecord
+			if err := doc.DataTo(&last); err != nil {
+				return fmt.Errorf("db: failed to decode audit record: %w", err)
+			}
+			prevSeq = last.Sequence
+			prevHash = last.Hash
+		}
+
+		rec = build(prevSeq, prevHash)
+		return tx.Set(f.Client.Collection(auditCollection).NewDoc(), rec)
+	})
+	if err != nil {
+		return AuditRecord{}, fmt.Errorf("db: failed to append audit record: %w", err)
+	}
+	return rec, nil
+}
+
+// ListAuditRecords returns every audit record with a timestamp in
+// [from, to] (either bound may be zero to mean "unbounded"), oldest first,
+// for GET /audit.
+func (f *FirestoreClient) ListAuditRecords(ctx context.Context, from, to time.Time) ([]AuditRecord, error) {
+	q := f.Client.Collection(auditCollection).OrderBy("timestamp", firestore.Asc)
+	if !from.IsZero() {
+		q = q.Where("timestamp", ">=", from)
+	}
+	if !to.IsZero() {
+		q = q.Where("timestamp", "<=", to)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var records []AuditRecord
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("db: failed to list audit records: %w", err)
+		}
+
+		var rec AuditRecord
+		if err := doc.DataTo(&rec); err != nil {
+			return nil, fmt.Errorf("db: failed to decode audit record %s: %w", doc.Ref.ID, err)
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
@@ -3,7 +3,6 @@ package db
 import (
 	"context"
 	"log"
-	"os"
 
 	firebase "firebase.google.com/go"
 	"cloud.google.com/go/firestore"
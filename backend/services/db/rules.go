@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/iterator"
+)
+
+const rulesCollection = "rules"
+
+// RuleDoc is an operator-authored JS rule stored in Firestore so it can be
+// edited and hot-reloaded without filesystem access to the host.
+type RuleDoc struct {
+	Name   string  `firestore:"name"`
+	Weight float64 `firestore:"weight"`
+	Source string  `firestore:"source"`
+}
+
+// ListRuleDocs returns every rule document in the rules collection.
+func (f *FirestoreClient) ListRuleDocs(ctx context.Context) ([]RuleDoc, error) {
+	iter := f.Client.Collection(rulesCollection).Documents(ctx)
+	defer iter.Stop()
+
+	var docs []RuleDoc
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("db: failed to list rule docs: %w", err)
+		}
+
+		var d RuleDoc
+		if err := doc.DataTo(&d); err != nil {
+			return nil, fmt.Errorf("db: failed to decode rule doc %s: %w", doc.Ref.ID, err)
+		}
+		docs = append(docs, d)
+	}
+
+	return docs, nil
+}
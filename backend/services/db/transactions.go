@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"asguard/services"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const transactionsCollection = "transactions"
+
+// TransactionRecord is the Firestore-persisted state of a single /analyze
+// call: the original request (so /rejudge can recompute it later), its
+// status while the worker processes it, and the final result once done.
+type TransactionRecord struct {
+	TransactionID string                    `firestore:"transaction_id"`
+	Tx            services.TransactionData  `firestore:"tx"`
+	Status        string                    `firestore:"status"` // "pending" | "done"
+	RuleVersion   string                    `firestore:"rule_version,omitempty"`
+	Result        *services.RiskResult      `firestore:"result,omitempty"`
+	Evidence      []services.EvidenceRecord `firestore:"evidence,omitempty"`
+	History       []TransactionHistoryEntry `firestore:"history,omitempty"`
+	CreatedAt     time.Time                 `firestore:"created_at"`
+	UpdatedAt     time.Time                 `firestore:"updated_at"`
+}
+
+// TransactionHistoryEntry snapshots a transaction's result and rule
+// version just before a rejudge overwrites them, so the new verdict can
+// be diffed against what was originally decided instead of the original
+// simply being lost.
+type TransactionHistoryEntry struct {
+	Result      services.RiskResult `firestore:"result"`
+	RuleVersion string              `firestore:"rule_version,omitempty"`
+	ReplacedAt  time.Time           `firestore:"replaced_at"`
+}
+
+// CreateTransactionPending records a newly enqueued transaction as
+// "pending" so GET /transactions/:id has something to return before the
+// worker finishes, and so /rejudge has the original request to recompute.
+func (f *FirestoreClient) CreateTransactionPending(ctx context.Context, tx services.TransactionData) error {
+	now := time.Now()
+	rec := TransactionRecord{
+		TransactionID: tx.TransactionID,
+		Tx:            tx,
+		Status:        "pending",
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	_, err := f.Client.Collection(transactionsCollection).Doc(tx.TransactionID).Set(ctx, rec)
+	if err != nil {
+		return fmt.Errorf("db: failed to record pending transaction %s: %w", tx.TransactionID, err)
+	}
+	return nil
+}
+
+// SaveTransactionResult persists a finished RiskResult and flips the
+// transaction's status to "done". If the transaction already has a
+// result — i.e. this is a /rejudge overwriting a prior verdict — that
+// verdict is archived to History first, so it isn't simply lost the
+// moment the rejudge completes.
+func (f *FirestoreClient) SaveTransactionResult(ctx context.Context, txID string, result services.RiskResult, ruleVersion string) error {
+	docRef := f.Client.Collection(transactionsCollection).Doc(txID)
+
+	existing, err := docRef.Get(ctx)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return fmt.Errorf("db: failed to look up existing result for transaction %s: %w", txID, err)
+	}
+	if err == nil {
+		var prev TransactionRecord
+		if err := existing.DataTo(&prev); err != nil {
+			return fmt.Errorf("db: failed to decode existing transaction %s: %w", txID, err)
+		}
+		if prev.Result != nil {
+			entry := TransactionHistoryEntry{
+				Result:      *prev.Result,
+				RuleVersion: prev.RuleVersion,
+				ReplacedAt:  time.Now(),
+			}
+			if _, err := docRef.Update(ctx, []firestore.Update{
+				{Path: "history", Value: firestore.ArrayUnion(entry)},
+			}); err != nil {
+				return fmt.Errorf("db: failed to archive prior result for transaction %s: %w", txID, err)
+			}
+		}
+	}
+
+	if _, err := docRef.Set(ctx, map[string]interface{}{
+		"status":       "done",
+		"result":       result,
+		"rule_version": ruleVersion,
+		"updated_at":   time.Now(),
+	}, firestore.MergeAll); err != nil {
+		return fmt.Errorf("db: failed to save result for transaction %s: %w", txID, err)
+	}
+	return nil
+}
+
+// AddEvidence appends a single evidence record to a transaction's
+// evidence list. The transaction doc must already exist (created by
+// CreateTransactionPending), since ArrayUnion updates in place rather than
+// creating the document.
+func (f *FirestoreClient) AddEvidence(ctx context.Context, txID string, rec services.EvidenceRecord) error {
+	_, err := f.Client.Collection(transactionsCollection).Doc(txID).Update(ctx, []firestore.Update{
+		{Path: "evidence", Value: firestore.ArrayUnion(rec)},
+		{Path: "updated_at", Value: time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("db: failed to add evidence to transaction %s: %w", txID, err)
+	}
+	return nil
+}
+
+// GetTransaction looks up a transaction's current status/result by ID.
+func (f *FirestoreClient) GetTransaction(ctx context.Context, txID string) (TransactionRecord, bool, error) {
+	doc, err := f.Client.Collection(transactionsCollection).Doc(txID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return TransactionRecord{}, false, nil
+	}
+	if err != nil {
+		return TransactionRecord{}, false, fmt.Errorf("db: failed to look up transaction %s: %w", txID, err)
+	}
+
+	var rec TransactionRecord
+	if err := doc.DataTo(&rec); err != nil {
+		return TransactionRecord{}, false, fmt.Errorf("db: failed to decode transaction %s: %w", txID, err)
+	}
+	return rec, true, nil
+}
+
+// RejudgeFilter narrows which transactions QueryForRejudge returns for
+// POST /rejudge to re-enqueue.
+// A zero value on any field means "don't filter on this".
+type RejudgeFilter struct {
+	UserID      string
+	RuleVersion string
+	From        time.Time
+	To          time.Time
+}
+
+// QueryForRejudge finds transactions matching filter so POST /rejudge can
+// re-enqueue them against the current rule engine / AI prompt.
+func (f *FirestoreClient) QueryForRejudge(ctx context.Context, filter RejudgeFilter) ([]TransactionRecord, error) {
+	q := f.Client.Collection(transactionsCollection).Query
+
+	if filter.UserID != "" {
+		q = q.Where("tx.UserID", "==", filter.UserID)
+	}
+	if filter.RuleVersion != "" {
+		q = q.Where("rule_version", "==", filter.RuleVersion)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("created_at", ">=", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("created_at", "<=", filter.To)
+	}
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var records []TransactionRecord
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("db: failed to query transactions for rejudge: %w", err)
+		}
+
+		var rec TransactionRecord
+		if err := doc.DataTo(&rec); err != nil {
+			return nil, fmt.Errorf("db: failed to decode transaction %s: %w", doc.Ref.ID, err)
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,18 @@
+package services
+
+import "time"
+
+// EvidenceRecord is the metadata for a single artifact attached to a
+// transaction — a device fingerprint dump, a geo-IP payload, a checkout
+// screenshot, a 3DS challenge log. The artifact bytes live in the evidence
+// object store; only this metadata is threaded through to Firestore and
+// the Groq prompt.
+type EvidenceRecord struct {
+	Key              string    `json:"key"`
+	Filename         string    `json:"filename"`
+	ContentType      string    `json:"content_type"`
+	SHA256           string    `json:"sha256"`
+	Size             int64     `json:"size"`
+	UploaderIdentity string    `json:"uploader_identity"`
+	UploadedAt       time.Time `json:"uploaded_at"`
+}
@@ -0,0 +1,73 @@
+// Package evidencestore wraps an S3-compatible object store (via minio-go)
+// for uploaded transaction evidence: device fingerprints, geo-IP payloads,
+// checkout screenshots, 3DS challenge logs. Only the bytes live here — the
+// metadata (filename, hash, uploader) is recorded in Firestore alongside
+// the transaction by the caller.
+package evidencestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// Store uploads and presigns objects in a single bucket.
+type Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewStoreFromEnv builds a Store from EVIDENCE_S3_ENDPOINT,
+// EVIDENCE_S3_BUCKET, EVIDENCE_S3_ACCESS_KEY, EVIDENCE_S3_SECRET_KEY, and
+// EVIDENCE_S3_USE_SSL (default "true"). It returns nil, nil when
+// EVIDENCE_S3_ENDPOINT isn't set, so evidence upload is optional the same
+// way Firestore and Redis are.
+func NewStoreFromEnv() (*Store, error) {
+	endpoint := os.Getenv("EVIDENCE_S3_ENDPOINT")
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(os.Getenv("EVIDENCE_S3_ACCESS_KEY"), os.Getenv("EVIDENCE_S3_SECRET_KEY"), ""),
+		Secure: os.Getenv("EVIDENCE_S3_USE_SSL") != "false",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("evidencestore: failed to create S3 client for %s: %w", endpoint, err)
+	}
+
+	return &Store{client: client, bucket: os.Getenv("EVIDENCE_S3_BUCKET")}, nil
+}
+
+// Upload streams r into objectKey and returns its SHA-256 hash, computed
+// on the fly rather than buffered, so large uploads don't double their
+// memory footprint.
+func (s *Store) Upload(ctx context.Context, objectKey string, r io.Reader, size int64, contentType string) (string, error) {
+	hasher := sha256.New()
+	tee := io.TeeReader(r, hasher)
+
+	_, err := s.client.PutObject(ctx, s.bucket, objectKey, tee, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("evidencestore: failed to upload %s: %w", objectKey, err)
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// PresignGet returns a short-lived, pre-signed download URL for objectKey.
+func (s *Store) PresignGet(ctx context.Context, objectKey string, ttl time.Duration) (string, error) {
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, objectKey, ttl, nil)
+	if err != nil {
+		return "", fmt.Errorf("evidencestore: failed to presign %s: %w", objectKey, err)
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,72 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"asguard/services"
+	"asguard/services/audit"
+	"asguard/services/db"
+
+	"github.com/hibiken/asynq"
+)
+
+// TransactionHandler processes queued transaction-analysis tasks and
+// persists their results to Firestore. Run by cmd/worker.
+type TransactionHandler struct {
+	Store   *db.FirestoreClient
+	Auditor audit.Auditor
+}
+
+// HandleAnalyzeTransactionTask runs the rule engine + Groq AI call for a
+// queued transaction, writes the resulting RiskResult back to Firestore
+// (flipping the transaction's status from "pending" to "done"), and
+// appends an audit log entry for it.
+func (h *TransactionHandler) HandleAnalyzeTransactionTask(ctx context.Context, t *asynq.Task) error {
+	var payload AnalyzeTransactionPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		// Malformed payloads will never succeed on retry, so give up now.
+		return fmt.Errorf("queue: invalid analyze payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	log.Printf("[WORKER] analyzing txn=%s rule_version=%s", payload.Tx.TransactionID, payload.RuleVersion)
+
+	// Evidence may have been uploaded after the transaction was first
+	// recorded (e.g. ahead of a /rejudge), and the payload carries none,
+	// so fetch the current list from Firestore at processing time.
+	var evidence []services.EvidenceRecord
+	if existing, found, err := h.Store.GetTransaction(ctx, payload.Tx.TransactionID); err != nil {
+		log.Printf("[WORKER] failed to look up existing evidence for txn=%s: %v", payload.Tx.TransactionID, err)
+	} else if found {
+		evidence = existing.Evidence
+	}
+
+	result := services.CalculateRisk(payload.Tx, evidence)
+
+	// result.RuleVersion (the hash CalculateRisk actually evaluated
+	// against), not payload.RuleVersion (whatever a /rejudge caller passed
+	// in to *select* transactions, or "" for a normal /analyze call) — the
+	// stored field is what QueryForRejudge filters future rejudges by.
+	if err := h.Store.SaveTransactionResult(ctx, payload.Tx.TransactionID, result, result.RuleVersion); err != nil {
+		return fmt.Errorf("queue: failed to save result for txn=%s: %w", payload.Tx.TransactionID, err)
+	}
+
+	if h.Auditor != nil {
+		entry := audit.Entry{
+			TransactionID:  payload.Tx.TransactionID,
+			CallerIdentity: payload.CallerIdentity,
+			Request:        payload.Tx,
+			RuleScores:     result.RuleScores,
+			AIPrompt:       result.AIPrompt,
+			AIRawResponse:  result.AIRawResponse,
+			Result:         result,
+		}
+		if err := h.Auditor.Append(ctx, entry); err != nil {
+			log.Printf("[WORKER] failed to append audit entry for txn=%s: %v", payload.Tx.TransactionID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+// Package queue defines the asynq task types and payloads shared between
+// the HTTP API (which enqueues) and cmd/worker (which processes them).
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"asguard/services"
+
+	"github.com/hibiken/asynq"
+)
+
+// TypeAnalyzeTransaction is the asynq task type for running the rule engine
+// and Groq AI call against a single transaction.
+const TypeAnalyzeTransaction = "analyze:transaction"
+
+// AnalyzeTransactionPayload is the JSON body of a TypeAnalyzeTransaction
+// task. RuleVersion is empty for a normal /analyze call; when /rejudge
+// re-enqueues a past transaction it carries the rule_version filter the
+// caller used to select it (empty if none). The worker only logs it.
+// CallerIdentity carries the agent/API-key identity that requested the
+// analysis through to the worker's audit log entry, since the worker has
+// no gin context of its own to read it from.
+type AnalyzeTransactionPayload struct {
+	Tx             services.TransactionData `json:"tx"`
+	RuleVersion    string                   `json:"rule_version,omitempty"`
+	CallerIdentity string                   `json:"caller_identity,omitempty"`
+}
+
+// NewAnalyzeTransactionTask builds the asynq task enqueued by /analyze and
+// /rejudge for a single transaction.
+func NewAnalyzeTransactionTask(tx services.TransactionData, ruleVersion, callerIdentity string) (*asynq.Task, error) {
+	payload, err := json.Marshal(AnalyzeTransactionPayload{Tx: tx, RuleVersion: ruleVersion, CallerIdentity: callerIdentity})
+	if err != nil {
+		return nil, fmt.Errorf("queue: failed to marshal analyze payload: %w", err)
+	}
+	return asynq.NewTask(TypeAnalyzeTransaction, payload), nil
+}
@@ -1,99 +1,72 @@
 package services
 
-import "log"
-
-// TransactionData represents the data needed for scoring
-type TransactionData struct {
-	UserID        string
-	TransactionID string
-	Amount        float64
-	Currency      string
-	IPAddress     string
-	DeviceID      string
-	Location      string
-}
+import (
+	"log"
+
+	"asguard/services/rules"
+)
+
+// TransactionData is an alias for rules.TransactionData: the rule engine
+// owns the canonical definition since it's what Rule implementations
+// evaluate, and every other package (ai_service.go, services/db,
+// services/queue, routes) keeps referring to services.TransactionData
+// unchanged.
+type TransactionData = rules.TransactionData
 
 // RiskResult is what we return after full scoring (rule-based + AI)
 type RiskResult struct {
-	Score              int      `json:"score"`
-	Level              string   `json:"level"`
-	Reasons            []string `json:"reasons"`
-	AITriggered        bool     `json:"ai_triggered"`
-	AIConfidence       float64  `json:"ai_confidence,omitempty"`
-	AISummary          string   `json:"ai_summary,omitempty"`
-	AIRecommendation   string   `json:"ai_recommendation,omitempty"`
-	AIFraudProbability float64  `json:"ai_fraud_probability,omitempty"`
+	Score              int               `json:"score"`
+	Level              string            `json:"level"`
+	Reasons            []string          `json:"reasons"`
+	RuleVersion        string            `json:"rule_version"`
+	AITriggered        bool              `json:"ai_triggered"`
+	AIConfidence       float64           `json:"ai_confidence,omitempty"`
+	AISummary          string            `json:"ai_summary,omitempty"`
+	AIRecommendation   string            `json:"ai_recommendation,omitempty"`
+	AIFraudProbability float64           `json:"ai_fraud_probability,omitempty"`
+	RuleScores         []rules.RuleScore `json:"-"` // per-rule breakdown, audit trail only
+	AIPrompt           string            `json:"-"` // exact Groq prompt, audit trail only
+	AIRawResponse      string            `json:"-"` // raw Groq response, audit trail only
 }
 
-// CalculateRisk runs the rule-based engine, then calls Groq AI if the score is high enough.
-func CalculateRisk(tx TransactionData) RiskResult {
-
-	reasons := []string{}
-
-	// -------------------------------------------------------
-	// RULE-BASED SCORING
-	// Each rule produces a risk value between 0.0 and 1.0.
-	// Weights must sum to 1.0 so the final score is a true percentage.
-	// -------------------------------------------------------
+// DefaultRegistry is the process-wide rule set CalculateRisk evaluates
+// against. It starts out holding just the built-in rules; script rules
+// from disk/Firestore are layered in via ReloadRules by main.go on startup
+// and SIGHUP, and by the POST /rules/reload handler.
+var DefaultRegistry = rules.NewRegistry(rules.DefaultRules())
 
-	// Rule 1: Transaction Amount (weight: 35%)
-	// Tiered — higher amounts carry more risk
-	amountRisk := 0.0
-	switch {
-	case tx.Amount > 500000:
-		amountRisk = 1.0
-		reasons = append(reasons, "Very high transaction amount (>500k)")
-	case tx.Amount > 100000:
-		amountRisk = 0.6
-		reasons = append(reasons, "High transaction amount (>100k)")
-	case tx.Amount > 50000:
-		amountRisk = 0.3
-		reasons = append(reasons, "Elevated transaction amount (>50k)")
-	}
+// ReloadRules rebuilds the active rule set — the built-ins plus any script
+// rules found in scriptDir (skipped if empty) plus extra (typically loaded
+// from Firestore) — and swaps it into DefaultRegistry.
+func ReloadRules(scriptDir string, extra []rules.RuleSource) error {
+	ruleSet := append([]rules.Rule{}, rules.DefaultRules()...)
 
-	// Rule 2: Currency (weight: 20%)
-	// Non-NGN transactions are higher risk in this context
-	currencyRisk := 0.0
-	if tx.Currency != "NGN" {
-		currencyRisk = 1.0
-		reasons = append(reasons, "Foreign currency transaction ("+tx.Currency+")")
+	if scriptDir != "" {
+		scripts, err := rules.LoadScriptsFromDir(scriptDir)
+		if err != nil {
+			return err
+		}
+		ruleSet = append(ruleSet, scripts...)
 	}
 
-	// Rule 3: Device ID (weight: 15%)
-	// Missing device = anonymous = higher risk
-	deviceRisk := 0.0
-	if tx.DeviceID == "" {
-		deviceRisk = 1.0
-		reasons = append(reasons, "Missing device ID")
+	for _, src := range extra {
+		ruleSet = append(ruleSet, rules.NewScriptRule(src.Name, src.Weight, src.Source))
 	}
 
-	// Rule 4: IP Address (weight: 15%)
-	// Missing IP = can't trace origin
-	ipRisk := 0.0
-	if tx.IPAddress == "" {
-		ipRisk = 1.0
-		reasons = append(reasons, "Missing IP address")
-	}
+	DefaultRegistry.Reload(ruleSet)
+	log.Printf("[RULES] reloaded %d rules, rule_version=%s", len(ruleSet), DefaultRegistry.Version())
+	return nil
+}
 
-	// Rule 5: Location (weight: 15%)
-	// Missing location = unverifiable origin
-	locationRisk := 0.0
-	if tx.Location == "" {
-		locationRisk = 1.0
-		reasons = append(reasons, "Missing location")
-	}
+// CalculateRisk runs the rule engine, then calls Groq AI if the score is
+// high enough. evidence (optional) is forwarded to the AI prompt when
+// present; the rule engine itself doesn't consider it.
+func CalculateRisk(tx TransactionData, evidence []EvidenceRecord) RiskResult {
 
 	// -------------------------------------------------------
-	// WEIGHTED FINAL SCORE (weights sum to exactly 1.0)
+	// RULE-BASED SCORING (delegated to the pluggable rule engine)
 	// -------------------------------------------------------
-	score := (amountRisk * 0.35) +
-		(currencyRisk * 0.20) +
-		(deviceRisk * 0.15) +
-		(ipRisk * 0.15) +
-		(locationRisk * 0.15)
-
-	// Convert to integer percentage (0–100)
-	finalScore := int(score * 100)
+	finalScore, reasons, ruleScores, ruleVersion := DefaultRegistry.Evaluate(tx)
 
 	// -------------------------------------------------------
 	// RISK LEVEL from rule-based score alone
@@ -111,12 +84,14 @@ func CalculateRisk(tx TransactionData) RiskResult {
 	// -------------------------------------------------------
 	aiTriggered := false
 	var aiResult AIResult
+	var aiInteraction AIInteraction
 
 	if finalScore >= 40 {
 		aiTriggered = true
 		log.Printf("[AI GATE] Score=%d for txn=%s — calling Groq AI...", finalScore, tx.TransactionID)
 
-		result, err := AnalyzeTransaction(tx, finalScore)
+		result, interaction, err := AnalyzeTransaction(tx, finalScore, evidence)
+		aiInteraction = interaction
 		if err != nil {
 			// AI failed — log it clearly and escalate to HIGH for safety
 			log.Printf("[AI ERROR] txn=%s: %v", tx.TransactionID, err)
@@ -150,10 +125,14 @@ func CalculateRisk(tx TransactionData) RiskResult {
 		Score:              finalScore,
 		Level:              level,
 		Reasons:            reasons,
+		RuleVersion:        ruleVersion,
 		AITriggered:        aiTriggered,
 		AIConfidence:       aiResult.Confidence,
 		AISummary:          aiResult.Reasoning,
 		AIRecommendation:   aiResult.RecommendedAction,
 		AIFraudProbability: aiResult.FraudProbability,
+		RuleScores:         ruleScores,
+		AIPrompt:           aiInteraction.Prompt,
+		AIRawResponse:      aiInteraction.RawResponse,
 	}
 }
@@ -0,0 +1,92 @@
+package rules
+
+import "fmt"
+
+// DefaultRules returns the five built-in rules that replicate Asguard's
+// original hardcoded scoring, with weights summing to 1.0.
+func DefaultRules() []Rule {
+	return []Rule{
+		&AmountRule{Weight: 0.35},
+		&CurrencyRule{Weight: 0.20},
+		&DeviceRule{Weight: 0.15},
+		&IPRule{Weight: 0.15},
+		&LocationRule{Weight: 0.15},
+	}
+}
+
+// weighted lets Registry.Reload sum configured weights without evaluating
+// every rule against a dummy transaction.
+type weighted interface {
+	weight() float64
+}
+
+// AmountRule scores higher transaction amounts as riskier.
+type AmountRule struct{ Weight float64 }
+
+func (r *AmountRule) Name() string   { return "amount" }
+func (r *AmountRule) weight() float64 { return r.Weight }
+
+func (r *AmountRule) Evaluate(tx TransactionData) (float64, float64, string) {
+	switch {
+	case tx.Amount > 500000:
+		return 1.0, r.Weight, "Very high transaction amount (>500k)"
+	case tx.Amount > 100000:
+		return 0.6, r.Weight, "High transaction amount (>100k)"
+	case tx.Amount > 50000:
+		return 0.3, r.Weight, "Elevated transaction amount (>50k)"
+	default:
+		return 0.0, r.Weight, ""
+	}
+}
+
+// CurrencyRule treats non-NGN transactions as higher risk in this context.
+type CurrencyRule struct{ Weight float64 }
+
+func (r *CurrencyRule) Name() string   { return "currency" }
+func (r *CurrencyRule) weight() float64 { return r.Weight }
+
+func (r *CurrencyRule) Evaluate(tx TransactionData) (float64, float64, string) {
+	if tx.Currency != "NGN" {
+		return 1.0, r.Weight, fmt.Sprintf("Foreign currency transaction (%s)", tx.Currency)
+	}
+	return 0.0, r.Weight, ""
+}
+
+// DeviceRule treats a missing device ID as anonymous, higher risk, traffic.
+type DeviceRule struct{ Weight float64 }
+
+func (r *DeviceRule) Name() string   { return "device" }
+func (r *DeviceRule) weight() float64 { return r.Weight }
+
+func (r *DeviceRule) Evaluate(tx TransactionData) (float64, float64, string) {
+	if tx.DeviceID == "" {
+		return 1.0, r.Weight, "Missing device ID"
+	}
+	return 0.0, r.Weight, ""
+}
+
+// IPRule treats a missing IP address as untraceable, higher risk, traffic.
+type IPRule struct{ Weight float64 }
+
+func (r *IPRule) Name() string   { return "ip_address" }
+func (r *IPRule) weight() float64 { return r.Weight }
+
+func (r *IPRule) Evaluate(tx TransactionData) (float64, float64, string) {
+	if tx.IPAddress == "" {
+		return 1.0, r.Weight, "Missing IP address"
+	}
+	return 0.0, r.Weight, ""
+}
+
+// LocationRule treats a missing location as an unverifiable origin.
+type LocationRule struct{ Weight float64 }
+
+func (r *LocationRule) Name() string   { return "location" }
+func (r *LocationRule) weight() float64 { return r.Weight }
+
+func (r *LocationRule) Evaluate(tx TransactionData) (float64, float64, string) {
+	if tx.Location == "" {
+		return 1.0, r.Weight, "Missing location"
+	}
+	return 0.0, r.Weight, ""
+}
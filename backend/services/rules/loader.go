@@ -0,0 +1,56 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RuleSource is an operator-authored JS rule loaded from a source other
+// than the rules/ directory, typically a Firestore collection so ops can
+// edit rules without filesystem access to the host.
+type RuleSource struct {
+	Name   string
+	Weight float64
+	Source string
+}
+
+// LoadScriptsFromDir reads every *.js file in dir into a ScriptRule. Files
+// are named "<weight-as-percent>_<name>.js" — e.g. "020_foreign_currency.js"
+// for a 0.20-weighted rule — so operators can see each rule's weight and
+// evaluation order at a glance.
+func LoadScriptsFromDir(dir string) ([]Rule, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("rules: failed to read rules dir %q: %w", dir, err)
+	}
+
+	var loaded []Rule
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".js") {
+			continue
+		}
+
+		source, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("rules: failed to read %q: %w", entry.Name(), err)
+		}
+
+		weight, name := parseScriptFilename(strings.TrimSuffix(entry.Name(), ".js"))
+		loaded = append(loaded, NewScriptRule(name, weight, string(source)))
+	}
+
+	return loaded, nil
+}
+
+func parseScriptFilename(stem string) (weight float64, name string) {
+	prefix, rest, found := strings.Cut(stem, "_")
+	if found {
+		if pct, err := strconv.Atoi(prefix); err == nil {
+			return float64(pct) / 100, rest
+		}
+	}
+	return 0, stem
+}
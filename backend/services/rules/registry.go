@@ -0,0 +1,110 @@
+package rules
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Registry composes a set of Rules into a single weighted risk score. It's
+// safe for concurrent use: Reload swaps in a new rule set atomically so a
+// SIGHUP or an admin POST /rules/reload never races an in-flight Evaluate.
+type Registry struct {
+	mu      sync.RWMutex
+	rules   []Rule
+	version string
+}
+
+// NewRegistry builds a Registry from an initial rule set.
+func NewRegistry(initial []Rule) *Registry {
+	reg := &Registry{}
+	reg.Reload(initial)
+	return reg
+}
+
+// Reload swaps in a new rule set and recomputes the registry's version
+// hash. It logs a warning (but doesn't refuse to load) if the weights
+// don't sum to 1.0, since that's almost always an operator mistake rather
+// than something worth hard-failing a reload over.
+func (reg *Registry) Reload(ruleSet []Rule) {
+	var total float64
+	for _, r := range ruleSet {
+		if w, ok := r.(weighted); ok {
+			total += w.weight()
+		}
+	}
+	if total < 0.99 || total > 1.01 {
+		log.Printf("[RULES] warning: rule weights sum to %.2f, not 1.0", total)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.rules = ruleSet
+	reg.version = computeVersion(ruleSet)
+}
+
+// RuleScore is one rule's contribution to a decision. The audit subsystem
+// records these alongside the final result so a reviewer can see exactly
+// how the rule-based score was composed, not just the total.
+type RuleScore struct {
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"`
+	Weight float64 `json:"weight"`
+}
+
+// Evaluate runs every active rule against tx and returns the weighted
+// score as an integer percentage (0-100), the non-empty reasons
+// contributing to it, the per-rule breakdown, and the rule_version it was
+// evaluated against.
+func (reg *Registry) Evaluate(tx TransactionData) (score int, reasons []string, breakdown []RuleScore, ruleVersion string) {
+	reg.mu.RLock()
+	ruleSet := reg.rules
+	ruleVersion = reg.version
+	reg.mu.RUnlock()
+
+	var total float64
+	for _, r := range ruleSet {
+		s, weight, reason := r.Evaluate(tx)
+		total += s * weight
+		breakdown = append(breakdown, RuleScore{Name: r.Name(), Score: s, Weight: weight})
+		if reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	// Weights aren't guaranteed to sum to 1.0 (Reload only warns, it
+	// doesn't refuse to load), so an aggressive custom rule stacked on top
+	// of the built-ins can push total above 1.0 — clamp so risk_score
+	// stays a valid 0-100 percentage regardless.
+	pct := int(total * 100)
+	if pct > 100 {
+		pct = 100
+	} else if pct < 0 {
+		pct = 0
+	}
+
+	return pct, reasons, breakdown, ruleVersion
+}
+
+// Version returns the hash of the currently active rule set.
+func (reg *Registry) Version() string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return reg.version
+}
+
+// computeVersion hashes each rule's identity (and, for script rules, its
+// source) so any change to the active rule set — a new threshold, a
+// rewritten script — produces a different rule_version on every decision.
+func computeVersion(ruleSet []Rule) string {
+	h := sha256.New()
+	for _, r := range ruleSet {
+		fmt.Fprintf(h, "%s\n", r.Name())
+		if sr, ok := r.(*ScriptRule); ok {
+			fmt.Fprintf(h, "%.4f\n%s\n", sr.Weight, sr.Source)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
@@ -0,0 +1,43 @@
+package rules
+
+import "testing"
+
+// stubRule always returns a fixed score/reason, for exercising
+// Registry.Evaluate's aggregation independent of any real rule's logic.
+type stubRule struct {
+	name  string
+	score float64
+	w     float64
+}
+
+func (r *stubRule) Name() string    { return r.name }
+func (r *stubRule) weight() float64 { return r.w }
+func (r *stubRule) Evaluate(TransactionData) (float64, float64, string) {
+	return r.score, r.w, "stub triggered"
+}
+
+func TestRegistryEvaluateClampsAboveHundred(t *testing.T) {
+	// Weights summing well past 1.0 — e.g. a custom rule stacked on top of
+	// built-ins that already sum to 1.0 — must not produce a risk_score
+	// over 100.
+	reg := NewRegistry([]Rule{
+		&stubRule{name: "a", score: 1, w: 1.0},
+		&stubRule{name: "b", score: 1, w: 0.5},
+	})
+
+	score, _, _, _ := reg.Evaluate(TransactionData{})
+	if score != 100 {
+		t.Errorf("score = %d, want 100", score)
+	}
+}
+
+func TestRegistryEvaluateWithinBounds(t *testing.T) {
+	reg := NewRegistry([]Rule{
+		&stubRule{name: "a", score: 0.5, w: 0.5},
+	})
+
+	score, _, _, _ := reg.Evaluate(TransactionData{})
+	if score != 25 {
+		t.Errorf("score = %d, want 25", score)
+	}
+}
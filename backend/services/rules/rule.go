@@ -0,0 +1,32 @@
+// Package rules implements Asguard's pluggable rule engine. Each Rule
+// contributes a weighted risk score that the Registry composes into the
+// rule-based portion of CalculateRisk, replacing the hardcoded switch
+// blocks that used to live there. Rules can be built-in Go types or
+// operator-authored JS loaded from disk or Firestore, so changing a
+// threshold no longer requires a redeploy.
+package rules
+
+// TransactionData is the data a Rule evaluates. It's the canonical
+// definition services.TransactionData aliases, since the rule engine is
+// what actually consumes these fields.
+type TransactionData struct {
+	UserID        string
+	TransactionID string
+	Amount        float64
+	Currency      string
+	IPAddress     string
+	DeviceID      string
+	Location      string
+}
+
+// Rule scores a single dimension of risk. Evaluate must return a score
+// between 0.0 (no risk) and 1.0 (maximum risk) for this dimension, the
+// rule's fixed weight, and a human-readable reason when score > 0 (empty
+// string otherwise, except that a ScriptRule whose script fails to
+// evaluate returns score 0 with the error as its reason). The Registry
+// multiplies score by weight and sums across all rules to produce the
+// final rule-based score.
+type Rule interface {
+	Evaluate(tx TransactionData) (score float64, weight float64, reason string)
+	Name() string
+}
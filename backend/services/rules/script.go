@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"fmt"
+
+	"github.com/dop251/goja"
+)
+
+// ScriptRule evaluates an operator-authored JS snippet against a
+// transaction, e.g.:
+//
+//	if (tx.Amount > 100000 && tx.Currency !== "NGN") {
+//	  return {score: 0.8, reason: "large foreign-currency transfer"};
+//	}
+//
+// The snippet runs as the body of a function taking tx; returning nothing
+// (or a falsy value) means "no risk" from this rule. Weight is fixed at
+// construction time, not controlled by the script.
+type ScriptRule struct {
+	RuleName string
+	Weight   float64
+	Source   string
+}
+
+// NewScriptRule builds a script-backed rule from source loaded off disk or
+// Firestore.
+func NewScriptRule(name string, weight float64, source string) *ScriptRule {
+	return &ScriptRule{RuleName: name, Weight: weight, Source: source}
+}
+
+func (r *ScriptRule) Name() string    { return r.RuleName }
+func (r *ScriptRule) weight() float64 { return r.Weight }
+
+func (r *ScriptRule) Evaluate(tx TransactionData) (float64, float64, string) {
+	vm := goja.New()
+	vm.Set("tx", map[string]interface{}{
+		"UserID":        tx.UserID,
+		"TransactionID": tx.TransactionID,
+		"Amount":        tx.Amount,
+		"Currency":      tx.Currency,
+		"IPAddress":     tx.IPAddress,
+		"DeviceID":      tx.DeviceID,
+		"Location":      tx.Location,
+	})
+
+	wrapped := fmt.Sprintf("(function(tx) {\n%s\n})(tx)", r.Source)
+	val, err := vm.RunString(wrapped)
+	if err != nil {
+		return 0, r.Weight, fmt.Sprintf("rule %q failed to evaluate: %v", r.RuleName, err)
+	}
+
+	result, ok := val.Export().(map[string]interface{})
+	if !ok {
+		// Script returned nothing (or something unexpected) — treat as no risk.
+		return 0, r.Weight, ""
+	}
+
+	score := toFloat64(result["score"])
+	reason, _ := result["reason"].(string)
+	return score, r.Weight, reason
+}
+
+// toFloat64 converts a JS-exported numeric value to float64. goja exports
+// whole-number JS values (e.g. the literal `1` in `{score: 1}`) as int64,
+// not float64, so a bare `.(float64)` assertion silently drops a script
+// rule's score to zero whenever it returns an integer — exactly the kind
+// of value an operator would write for "maximum risk".
+func toFloat64(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,67 @@
+package rules
+
+import "testing"
+
+func TestScriptRuleEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		source    string
+		wantScore float64
+	}{
+		{
+			name:      "integer literal score",
+			source:    `return {score: 1, reason: "max risk"};`,
+			wantScore: 1,
+		},
+		{
+			name:      "float literal score",
+			source:    `return {score: 0.8, reason: "partial risk"};`,
+			wantScore: 0.8,
+		},
+		{
+			name:      "computed integer score",
+			source:    `if (tx.Amount > 100000) { return {score: 1}; }`,
+			wantScore: 1,
+		},
+		{
+			name:      "no return means no risk",
+			source:    `if (false) { return {score: 1}; }`,
+			wantScore: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewScriptRule("test_rule", 1.0, tt.source)
+			score, weight, _ := r.Evaluate(TransactionData{Amount: 200000})
+			if score != tt.wantScore {
+				t.Errorf("Evaluate() score = %v, want %v", score, tt.wantScore)
+			}
+			if weight != 1.0 {
+				t.Errorf("Evaluate() weight = %v, want %v", weight, 1.0)
+			}
+		})
+	}
+}
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want float64
+	}{
+		{"float64", float64(0.5), 0.5},
+		{"int64", int64(1), 1},
+		{"int", int(1), 1},
+		{"nil", nil, 0},
+		{"string", "not a number", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toFloat64(tt.in); got != tt.want {
+				t.Errorf("toFloat64(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}